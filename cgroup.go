@@ -0,0 +1,43 @@
+package main
+
+// defaultCgroupRoot is the conventional mount point of the cgroup
+// filesystem(s) on Linux.
+const defaultCgroupRoot = "/sys/fs/cgroup"
+
+// CgroupCpuStat holds the cpu accounting counters of a cgroup: cumulative
+// usage in nanoseconds, and the CFS quota/period (in microseconds) that
+// bounds it, if any. QuotaUs is -1 when the cgroup has no quota set.
+type CgroupCpuStat struct {
+	UsageNanos uint64
+	QuotaUs    int64
+	PeriodUs   int64
+
+	UsagePercent float64 `json:"usage_p"`
+}
+
+// CgroupMemStat holds memory accounting for a cgroup, in bytes.
+type CgroupMemStat struct {
+	Usage      uint64
+	Rss        uint64
+	Cache      uint64
+	Swap       uint64
+	PgMajFault uint64 `json:"pgmajfault"`
+}
+
+// CgroupBlkioDeviceStat holds the cumulative block IO byte and op counters
+// for a single device, as accounted to a cgroup.
+type CgroupBlkioDeviceStat struct {
+	Device     string
+	ReadBytes  uint64 `json:"read_bytes"`
+	WriteBytes uint64 `json:"write_bytes"`
+	ReadCount  uint64 `json:"read_count"`
+	WriteCount uint64 `json:"write_count"`
+}
+
+// CgroupStat is a single sample of a cgroup's resource accounting.
+type CgroupStat struct {
+	Path  string
+	Cpu   CgroupCpuStat
+	Mem   CgroupMemStat
+	Blkio []CgroupBlkioDeviceStat
+}
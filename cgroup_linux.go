@@ -0,0 +1,353 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// isCgroupV2 reports whether the host exposes the unified cgroups v2
+// hierarchy at root.
+func isCgroupV2(root string) bool {
+	_, err := os.Stat(filepath.Join(root, "cgroup.controllers"))
+	return err == nil
+}
+
+// primarySubsystemDir returns the v1 subsystem directory used as the
+// reference hierarchy for enumeration and path resolution. It is irrelevant
+// on v2 hosts, where all controllers share a single hierarchy.
+func primarySubsystemDir(root string) string {
+
+	for _, subsys := range []string{"cpu,cpuacct", "cpuacct,cpu", "cpu"} {
+		if _, err := os.Stat(filepath.Join(root, subsys)); err == nil {
+			return subsys
+		}
+	}
+	return "cpu"
+}
+
+// CgroupPathForPid returns the cgroup path (relative to its hierarchy root)
+// that pid belongs to, by reading /proc/<pid>/cgroup. On cgroups v1 hosts
+// the path of the cpu/cpuacct controller is preferred, falling back to
+// whatever subsystem is listed first.
+func CgroupPathForPid(pid int) (string, error) {
+
+	content, err := ioutil.ReadFile(fmt.Sprintf("/proc/%d/cgroup", pid))
+	if err != nil {
+		return "", err
+	}
+
+	var fallback string
+	scanner := bufio.NewScanner(strings.NewReader(string(content)))
+	for scanner.Scan() {
+		// format: hierarchy-ID:controller-list:cgroup-path
+		fields := strings.SplitN(scanner.Text(), ":", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		if fields[1] == "" {
+			// cgroups v2: a single unified hierarchy
+			return fields[2], nil
+		}
+		for _, c := range strings.Split(fields[1], ",") {
+			if c == "cpu" || c == "cpuacct" {
+				return fields[2], nil
+			}
+		}
+		if fallback == "" {
+			fallback = fields[2]
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+
+	if fallback != "" {
+		return fallback, nil
+	}
+	return "", fmt.Errorf("no cgroup entry found for pid %d", pid)
+}
+
+// EnumerateCgroups walks the hierarchy under parent and returns every
+// cgroup path found, relative to the hierarchy root, so they can be passed
+// straight to ReadCgroupStats.
+func EnumerateCgroups(root, parent string) ([]string, error) {
+
+	hierarchyRoot := root
+	if !isCgroupV2(root) {
+		hierarchyRoot = filepath.Join(root, primarySubsystemDir(root))
+	}
+	base := filepath.Join(hierarchyRoot, parent)
+
+	var paths []string
+	err := filepath.Walk(base, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(hierarchyRoot, p)
+		if err != nil {
+			return err
+		}
+		paths = append(paths, "/"+filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return paths, nil
+}
+
+// ReadCgroupStats reads the cpu, memory and blkio accounting files of the
+// cgroup at path (relative to root) and returns their raw, cumulative
+// values. Missing memory/blkio controllers and missing accounting files are
+// skipped rather than treated as a hard error, since not every cgroup has
+// every controller attached; only a missing cgroup directory (on v1, the
+// one in the cpu hierarchy) is returned as an error.
+func ReadCgroupStats(root, path string) (*CgroupStat, error) {
+
+	stat := &CgroupStat{Path: path}
+
+	if isCgroupV2(root) {
+		dir := filepath.Join(root, path)
+		if _, err := os.Stat(dir); err != nil {
+			return nil, err
+		}
+		readCgroupV2Cpu(dir, &stat.Cpu)
+		readCgroupV2Mem(dir, &stat.Mem)
+		stat.Blkio = readCgroupV2Blkio(dir)
+		return stat, nil
+	}
+
+	cpuDir := filepath.Join(root, primarySubsystemDir(root), path)
+	if _, err := os.Stat(cpuDir); err != nil {
+		return nil, err
+	}
+	readCgroupV1Cpu(cpuDir, &stat.Cpu)
+	readCgroupV1Mem(filepath.Join(root, "memory", path), &stat.Mem)
+	stat.Blkio = readCgroupV1Blkio(filepath.Join(root, "blkio", path))
+
+	return stat, nil
+}
+
+func readCgroupV1Cpu(dir string, cpu *CgroupCpuStat) {
+
+	cpu.UsageNanos = readCgroupUint(filepath.Join(dir, "cpuacct.usage"))
+	cpu.QuotaUs = readCgroupInt(filepath.Join(dir, "cpu.cfs_quota_us"), -1)
+	cpu.PeriodUs = readCgroupInt(filepath.Join(dir, "cpu.cfs_period_us"), 0)
+}
+
+func readCgroupV1Mem(dir string, mem *CgroupMemStat) {
+
+	mem.Usage = readCgroupUint(filepath.Join(dir, "memory.usage_in_bytes"))
+
+	fields := readCgroupKeyValueFile(filepath.Join(dir, "memory.stat"))
+	mem.Rss = fields["rss"]
+	mem.Cache = fields["cache"]
+	mem.Swap = fields["swap"]
+	mem.PgMajFault = fields["pgmajfault"]
+}
+
+func readCgroupV1Blkio(dir string) []CgroupBlkioDeviceStat {
+
+	readBytes := readCgroupBlkioFile(filepath.Join(dir, "blkio.io_service_bytes"), "Read")
+	writeBytes := readCgroupBlkioFile(filepath.Join(dir, "blkio.io_service_bytes"), "Write")
+	readCount := readCgroupBlkioFile(filepath.Join(dir, "blkio.io_serviced"), "Read")
+	writeCount := readCgroupBlkioFile(filepath.Join(dir, "blkio.io_serviced"), "Write")
+
+	devices := map[string]*CgroupBlkioDeviceStat{}
+	get := func(dev string) *CgroupBlkioDeviceStat {
+		d, ok := devices[dev]
+		if !ok {
+			d = &CgroupBlkioDeviceStat{Device: dev}
+			devices[dev] = d
+		}
+		return d
+	}
+	for dev, bytes := range readBytes {
+		get(dev).ReadBytes = bytes
+	}
+	for dev, bytes := range writeBytes {
+		get(dev).WriteBytes = bytes
+	}
+	for dev, count := range readCount {
+		get(dev).ReadCount = count
+	}
+	for dev, count := range writeCount {
+		get(dev).WriteCount = count
+	}
+
+	stats := make([]CgroupBlkioDeviceStat, 0, len(devices))
+	for _, d := range devices {
+		stats = append(stats, *d)
+	}
+	return stats
+}
+
+func readCgroupV2Cpu(dir string, cpu *CgroupCpuStat) {
+
+	fields := readCgroupKeyValueFile(filepath.Join(dir, "cpu.stat"))
+	cpu.UsageNanos = fields["usage_usec"] * 1000
+
+	quota, period := readCgroupV2MaxFile(filepath.Join(dir, "cpu.max"))
+	cpu.QuotaUs = quota
+	cpu.PeriodUs = period
+}
+
+func readCgroupV2Mem(dir string, mem *CgroupMemStat) {
+
+	mem.Usage = readCgroupUint(filepath.Join(dir, "memory.current"))
+
+	fields := readCgroupKeyValueFile(filepath.Join(dir, "memory.stat"))
+	mem.Rss = fields["anon"]
+	mem.Cache = fields["file"]
+	mem.PgMajFault = fields["pgmajfault"]
+	mem.Swap = readCgroupUint(filepath.Join(dir, "memory.swap.current"))
+}
+
+func readCgroupV2Blkio(dir string) []CgroupBlkioDeviceStat {
+
+	content, err := ioutil.ReadFile(filepath.Join(dir, "io.stat"))
+	if err != nil {
+		return nil
+	}
+
+	var stats []CgroupBlkioDeviceStat
+	scanner := bufio.NewScanner(strings.NewReader(string(content)))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		dev := CgroupBlkioDeviceStat{Device: fields[0]}
+		for _, kv := range fields[1:] {
+			parts := strings.SplitN(kv, "=", 2)
+			if len(parts) != 2 {
+				continue
+			}
+			value, err := strconv.ParseUint(parts[1], 10, 64)
+			if err != nil {
+				continue
+			}
+			switch parts[0] {
+			case "rbytes":
+				dev.ReadBytes = value
+			case "wbytes":
+				dev.WriteBytes = value
+			case "rios":
+				dev.ReadCount = value
+			case "wios":
+				dev.WriteCount = value
+			}
+		}
+		stats = append(stats, dev)
+	}
+
+	return stats
+}
+
+// readCgroupV2MaxFile parses a "<quota> <period>" file such as cpu.max,
+// where quota may be the literal string "max" to mean unlimited.
+func readCgroupV2MaxFile(path string) (quotaUs, periodUs int64) {
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return -1, 0
+	}
+
+	fields := strings.Fields(string(content))
+	if len(fields) != 2 {
+		return -1, 0
+	}
+
+	if fields[0] == "max" {
+		quotaUs = -1
+	} else {
+		quotaUs, _ = strconv.ParseInt(fields[0], 10, 64)
+	}
+	periodUs, _ = strconv.ParseInt(fields[1], 10, 64)
+	return quotaUs, periodUs
+}
+
+func readCgroupUint(path string) uint64 {
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+	value, _ := strconv.ParseUint(strings.TrimSpace(string(content)), 10, 64)
+	return value
+}
+
+func readCgroupInt(path string, def int64) int64 {
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return def
+	}
+	value, err := strconv.ParseInt(strings.TrimSpace(string(content)), 10, 64)
+	if err != nil {
+		return def
+	}
+	return value
+}
+
+// readCgroupKeyValueFile parses files made of "<key> <value>" lines, such
+// as memory.stat or cpu.stat.
+func readCgroupKeyValueFile(path string) map[string]uint64 {
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return map[string]uint64{}
+	}
+
+	fields := map[string]uint64{}
+	scanner := bufio.NewScanner(strings.NewReader(string(content)))
+	for scanner.Scan() {
+		parts := strings.Fields(scanner.Text())
+		if len(parts) != 2 {
+			continue
+		}
+		value, err := strconv.ParseUint(parts[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		fields[parts[0]] = value
+	}
+
+	return fields
+}
+
+// readCgroupBlkioFile parses a blkio.io_service_bytes-style file and sums
+// the counters of the given op ("Read" or "Write") per device, keyed by
+// "major:minor".
+func readCgroupBlkioFile(path, op string) map[string]uint64 {
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return map[string]uint64{}
+	}
+
+	devices := map[string]uint64{}
+	scanner := bufio.NewScanner(strings.NewReader(string(content)))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 || fields[1] != op {
+			continue
+		}
+		value, err := strconv.ParseUint(fields[2], 10, 64)
+		if err != nil {
+			continue
+		}
+		devices[fields[0]] += value
+	}
+
+	return devices
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func writeFixture(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatalf("writing fixture %s: %v", name, err)
+	}
+}
+
+func TestReadCgroupKeyValueFile(t *testing.T) {
+
+	dir := t.TempDir()
+	writeFixture(t, dir, "memory.stat", "cache 1024\nrss 2048\nswap 0\npgmajfault 3\n")
+
+	fields := readCgroupKeyValueFile(filepath.Join(dir, "memory.stat"))
+
+	if fields["cache"] != 1024 {
+		t.Errorf("cache = %d, want 1024", fields["cache"])
+	}
+	if fields["rss"] != 2048 {
+		t.Errorf("rss = %d, want 2048", fields["rss"])
+	}
+	if fields["pgmajfault"] != 3 {
+		t.Errorf("pgmajfault = %d, want 3", fields["pgmajfault"])
+	}
+}
+
+func TestReadCgroupV1Blkio(t *testing.T) {
+
+	dir := t.TempDir()
+	writeFixture(t, dir, "blkio.io_service_bytes",
+		"8:0 Read 1000\n8:0 Write 2000\n8:0 Total 3000\n253:0 Read 500\n")
+	writeFixture(t, dir, "blkio.io_serviced",
+		"8:0 Read 10\n8:0 Write 20\n8:0 Total 30\n253:0 Read 5\n")
+
+	stats := readCgroupV1Blkio(dir)
+
+	byDevice := make(map[string]CgroupBlkioDeviceStat, len(stats))
+	for _, s := range stats {
+		byDevice[s.Device] = s
+	}
+
+	dev0, ok := byDevice["8:0"]
+	if !ok {
+		t.Fatalf("expected a stat for device 8:0, got %v", stats)
+	}
+	if dev0.ReadBytes != 1000 || dev0.WriteBytes != 2000 {
+		t.Errorf("8:0 bytes = %d/%d, want 1000/2000", dev0.ReadBytes, dev0.WriteBytes)
+	}
+	if dev0.ReadCount != 10 || dev0.WriteCount != 20 {
+		t.Errorf("8:0 counts = %d/%d, want 10/20", dev0.ReadCount, dev0.WriteCount)
+	}
+
+	dev1, ok := byDevice["253:0"]
+	if !ok {
+		t.Fatalf("expected a stat for device 253:0, got %v", stats)
+	}
+	if dev1.ReadBytes != 500 || dev1.ReadCount != 5 {
+		t.Errorf("253:0 = %d bytes / %d count, want 500/5", dev1.ReadBytes, dev1.ReadCount)
+	}
+}
+
+func TestReadCgroupV2Blkio(t *testing.T) {
+
+	dir := t.TempDir()
+	writeFixture(t, dir, "io.stat",
+		"8:0 rbytes=1000 wbytes=2000 rios=10 wios=20\n253:0 rbytes=500 wbytes=0 rios=5 wios=0\n")
+
+	stats := readCgroupV2Blkio(dir)
+
+	byDevice := make(map[string]CgroupBlkioDeviceStat, len(stats))
+	for _, s := range stats {
+		byDevice[s.Device] = s
+	}
+
+	dev0, ok := byDevice["8:0"]
+	if !ok {
+		t.Fatalf("expected a stat for device 8:0, got %v", stats)
+	}
+	if dev0.ReadBytes != 1000 || dev0.WriteBytes != 2000 {
+		t.Errorf("8:0 bytes = %d/%d, want 1000/2000", dev0.ReadBytes, dev0.WriteBytes)
+	}
+	if dev0.ReadCount != 10 || dev0.WriteCount != 20 {
+		t.Errorf("8:0 counts = %d/%d, want 10/20", dev0.ReadCount, dev0.WriteCount)
+	}
+}
+
+func TestReadCgroupV2MaxFile(t *testing.T) {
+
+	dir := t.TempDir()
+	writeFixture(t, dir, "cpu.max", "50000 100000\n")
+
+	quota, period := readCgroupV2MaxFile(filepath.Join(dir, "cpu.max"))
+	if quota != 50000 || period != 100000 {
+		t.Errorf("quota/period = %d/%d, want 50000/100000", quota, period)
+	}
+
+	writeFixture(t, dir, "unlimited.max", "max 100000\n")
+	quota, period = readCgroupV2MaxFile(filepath.Join(dir, "unlimited.max"))
+	if quota != -1 || period != 100000 {
+		t.Errorf("quota/period = %d/%d, want -1/100000", quota, period)
+	}
+}
@@ -0,0 +1,25 @@
+// +build !linux
+
+package main
+
+import "fmt"
+
+var errCgroupsUnsupported = fmt.Errorf("cgroups are not supported on this platform")
+
+// CgroupPathForPid is not supported outside Linux: cgroups are a Linux
+// kernel feature with no equivalent elsewhere.
+func CgroupPathForPid(pid int) (string, error) {
+	return "", errCgroupsUnsupported
+}
+
+// EnumerateCgroups is not supported outside Linux: cgroups are a Linux
+// kernel feature with no equivalent elsewhere.
+func EnumerateCgroups(root, parent string) ([]string, error) {
+	return nil, errCgroupsUnsupported
+}
+
+// ReadCgroupStats is not supported outside Linux: cgroups are a Linux
+// kernel feature with no equivalent elsewhere.
+func ReadCgroupStats(root, path string) (*CgroupStat, error) {
+	return nil, errCgroupsUnsupported
+}
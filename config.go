@@ -0,0 +1,54 @@
+package main
+
+import (
+	"github.com/elastic/libbeat/logp"
+	"github.com/elastic/libbeat/outputs"
+	"github.com/elastic/libbeat/publisher"
+)
+
+// ProcsConfig configures which processes topbeat should track. Every
+// selector that is set is resolved independently and the results are
+// combined, so a process matching any one of them is tracked.
+type ProcsConfig struct {
+	Pattern     *[]string
+	FullPattern *[]string
+	PidFile     *string
+	Exe         *[]string
+	User        *string
+}
+
+// CgroupsConfig configures cgroup-aware container resource reporting.
+type CgroupsConfig struct {
+	Root *string
+	// Parent restricts enumeration (see EnumerateAll) to cgroups below this
+	// path, e.g. "docker" or "kubepods". The cgroups of tracked processes
+	// are not filtered by it.
+	Parent *string
+	// EnumerateAll reports every cgroup found under Parent. When false
+	// (the default), only the cgroups of the tracked processes (see
+	// ProcsConfig) are reported.
+	EnumerateAll *bool
+}
+
+// TopConfig is the configuration of the topbeat input, as read from the
+// "input" section of the config file.
+type TopConfig struct {
+	Period     *int64
+	Procs      *ProcsConfig
+	Devices    *[]string
+	Interfaces *[]string
+	Cgroups    *CgroupsConfig
+	// StatsBackend selects the StatsProvider implementation: "native" or
+	// "gopsutil" (the default).
+	StatsBackend *string
+}
+
+// ConfigSettings holds the whole topbeat configuration file.
+type ConfigSettings struct {
+	Input   TopConfig
+	Output  map[string]outputs.MothershipConfig
+	Shipper publisher.ShipperConfig
+	Logging logp.Logging
+}
+
+var Config ConfigSettings
@@ -0,0 +1,11 @@
+package main
+
+// DiskIOCounters holds the cumulative per-device disk I/O counters, however
+// they were sourced on the current platform.
+type DiskIOCounters struct {
+	Name       string
+	ReadCount  uint64
+	WriteCount uint64
+	ReadBytes  uint64
+	WriteBytes uint64
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// the sector size used by the kernel when reporting diskstats, regardless of
+// the device's actual physical sector size.
+const diskstatsSectorSize = 512
+
+// GetDiskIO reads /proc/diskstats and returns the cumulative I/O counters
+// for every block device found there.
+func GetDiskIO() (map[string]*DiskIOCounters, error) {
+
+	file, err := os.Open("/proc/diskstats")
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return parseDiskStats(file)
+}
+
+// parseDiskStats parses the /proc/diskstats format out of r.
+func parseDiskStats(r io.Reader) (map[string]*DiskIOCounters, error) {
+
+	counters := map[string]*DiskIOCounters{}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		// major minor name rd_ios rd_merges rd_sectors rd_ticks
+		// wr_ios wr_merges wr_sectors wr_ticks ...
+		if len(fields) < 14 {
+			continue
+		}
+
+		name := fields[2]
+		readCount, _ := strconv.ParseUint(fields[3], 10, 64)
+		readSectors, _ := strconv.ParseUint(fields[5], 10, 64)
+		writeCount, _ := strconv.ParseUint(fields[7], 10, 64)
+		writeSectors, _ := strconv.ParseUint(fields[9], 10, 64)
+
+		counters[name] = &DiskIOCounters{
+			Name:       name,
+			ReadCount:  readCount,
+			WriteCount: writeCount,
+			ReadBytes:  readSectors * diskstatsSectorSize,
+			WriteBytes: writeSectors * diskstatsSectorSize,
+		}
+	}
+
+	return counters, scanner.Err()
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+const sampleDiskStats = `   8       0 sda 1000 50 20000 100 500 20 10000 200 0 150 300
+   8       1 sda1 900 40 18000 90 400 10 9000 180 0 120 270
+ 259       0 nvme0n1 2000 0 40000 50 1000 0 20000 60 0 80 110
+`
+
+func TestParseDiskStats(t *testing.T) {
+
+	counters, err := parseDiskStats(strings.NewReader(sampleDiskStats))
+	if err != nil {
+		t.Fatalf("parseDiskStats returned error: %v", err)
+	}
+
+	sda, ok := counters["sda"]
+	if !ok {
+		t.Fatalf("expected a counter for sda, got %v", counters)
+	}
+	if sda.ReadCount != 1000 {
+		t.Errorf("ReadCount = %d, want 1000", sda.ReadCount)
+	}
+	if sda.WriteCount != 500 {
+		t.Errorf("WriteCount = %d, want 500", sda.WriteCount)
+	}
+	if want := uint64(20000 * diskstatsSectorSize); sda.ReadBytes != want {
+		t.Errorf("ReadBytes = %d, want %d", sda.ReadBytes, want)
+	}
+	if want := uint64(10000 * diskstatsSectorSize); sda.WriteBytes != want {
+		t.Errorf("WriteBytes = %d, want %d", sda.WriteBytes, want)
+	}
+
+	if _, ok := counters["nvme0n1"]; !ok {
+		t.Errorf("expected a counter for nvme0n1, got %v", counters)
+	}
+}
+
+func TestParseDiskStatsSkipsShortLines(t *testing.T) {
+
+	counters, err := parseDiskStats(strings.NewReader("   8       0 sda 1 2 3\n"))
+	if err != nil {
+		t.Fatalf("parseDiskStats returned error: %v", err)
+	}
+	if len(counters) != 0 {
+		t.Errorf("expected no counters from a malformed line, got %v", counters)
+	}
+}
@@ -0,0 +1,9 @@
+package main
+
+// GetDiskIO is not yet implemented on Windows: per-device byte/op counters
+// require the PDH "LogicalDisk"/"PhysicalDisk" counters, which needs more
+// plumbing than a stub should take on. Rather than leave the package
+// unbuildable on Windows, report no devices until that's wired up.
+func GetDiskIO() (map[string]*DiskIOCounters, error) {
+	return map[string]*DiskIOCounters{}, nil
+}
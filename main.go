@@ -23,18 +23,80 @@ var Name = "topbeat"
 type ProcsMap map[int]*Process
 
 type Topbeat struct {
-	isAlive      bool
-	period       time.Duration
-	procs        []string
-	procsMap     ProcsMap
-	lastCpuTimes *CpuTimes
+	isAlive             bool
+	period              time.Duration
+	procsConfig         *ProcsConfig
+	finder              PIDFinder
+	stats               StatsProvider
+	procsMap            ProcsMap
+	lastCpuTimes        *CpuTimes
+	lastPerCoreCpuTimes []*CpuTimes
+
+	devices    []string
+	interfaces []string
+
+	lastDiskIOCounters map[string]*DiskIOCounters
+	lastNetIOCounters  map[string]*NetIOCounters
+
+	cgroupsConfig   *CgroupsConfig
+	lastCgroupStats map[string]*CgroupStat
 
 	events chan common.MapStr
 }
 
-func (t *Topbeat) MatchProcess(name string) bool {
+// matchedPids resolves the configured process selectors into the set of
+// pids currently matching any one of them.
+func (t *Topbeat) matchedPids() []int {
+
+	if t.procsConfig == nil {
+		return nil
+	}
 
-	for _, reg := range t.procs {
+	pidSet := map[int]bool{}
+	collect := func(pids []int, err error) {
+		if err != nil {
+			logp.Warn("Matching processes: %v", err)
+			return
+		}
+		for _, pid := range pids {
+			pidSet[pid] = true
+		}
+	}
+
+	if t.procsConfig.Pattern != nil {
+		for _, expr := range *t.procsConfig.Pattern {
+			collect(t.finder.Pattern(expr))
+		}
+	}
+	if t.procsConfig.FullPattern != nil {
+		for _, expr := range *t.procsConfig.FullPattern {
+			collect(t.finder.FullPattern(expr))
+		}
+	}
+	if t.procsConfig.Exe != nil {
+		for _, expr := range *t.procsConfig.Exe {
+			collect(t.finder.Exe(expr))
+		}
+	}
+	if t.procsConfig.PidFile != nil {
+		collect(t.finder.PidFile(*t.procsConfig.PidFile))
+	}
+	if t.procsConfig.User != nil {
+		collect(t.finder.Uid(*t.procsConfig.User))
+	}
+
+	pids := make([]int, 0, len(pidSet))
+	for pid := range pidSet {
+		pids = append(pids, pid)
+	}
+	return pids
+}
+
+// matchAny returns true if name matches any of the given regular
+// expressions. An empty (or invalid) list matches nothing.
+func matchAny(patterns []string, name string) bool {
+
+	for _, reg := range patterns {
 		matched, _ := regexp.MatchString(reg, name)
 		if matched {
 			return true
@@ -55,7 +117,7 @@ func (t *Topbeat) getUsedMemPercent(m *MemStat) float64 {
 
 func (t *Topbeat) getRssPercent(m *ProcMemStat) float64 {
 
-	mem_stat, err := GetMemory()
+	mem_stat, err := t.stats.GetMemory()
 	if err != nil {
 		logp.Warn("Getting memory details: %v", err)
 		return 0.0
@@ -67,21 +129,68 @@ func (t *Topbeat) getRssPercent(m *ProcMemStat) float64 {
 	return Round(perc, .5, 2)
 }
 
-func (t *Topbeat) getCpuPercentage(t2 *CpuTimes) float64 {
+// getCpuPercentages computes, for every CPU mode, the share of the delta
+// between t2 and the last sample it was spent in.
+func (t *Topbeat) getCpuPercentages(t2 *CpuTimes) CpuPercentages {
 
 	t1 := t.lastCpuTimes
+	t.lastCpuTimes = t2
+
+	perc := CpuPercentages{}
+	if t1 == nil {
+		return perc
+	}
+
+	all_delta := float64(t2.sum() - t1.sum())
+	if all_delta == 0 {
+		return perc
+	}
+
+	perc.User = Round(100*float64(t2.User-t1.User)/all_delta, .5, 2)
+	perc.Nice = Round(100*float64(t2.Nice-t1.Nice)/all_delta, .5, 2)
+	perc.System = Round(100*float64(t2.System-t1.System)/all_delta, .5, 2)
+	perc.Idle = Round(100*float64(t2.Idle-t1.Idle)/all_delta, .5, 2)
+	perc.IOWait = Round(100*float64(t2.IOWait-t1.IOWait)/all_delta, .5, 2)
+	perc.Irq = Round(100*float64(t2.Irq-t1.Irq)/all_delta, .5, 2)
+	perc.SoftIrq = Round(100*float64(t2.SoftIrq-t1.SoftIrq)/all_delta, .5, 2)
+	perc.Steal = Round(100*float64(t2.Steal-t1.Steal)/all_delta, .5, 2)
+	perc.Guest = Round(100*float64(t2.Guest-t1.Guest)/all_delta, .5, 2)
+
+	return perc
+}
 
-	perc := 0.0
+// getPerCoreCpuPercentages mirrors getCpuPercentages for each core,
+// matching cores up by index against the previous sample.
+func (t *Topbeat) getPerCoreCpuPercentages(cores []*CpuTimes) []CpuPercentages {
 
-	if t1 != nil {
-		all_delta := t2.sum() - t1.sum()
-		user_delta := t2.User - t1.User
+	prev := t.lastPerCoreCpuTimes
+	t.lastPerCoreCpuTimes = cores
 
-		perc = float64(100*user_delta) / float64(all_delta)
+	percs := make([]CpuPercentages, len(cores))
+	if prev == nil || len(prev) != len(cores) {
+		return percs
 	}
-	t.lastCpuTimes = t2
 
-	return Round(perc, .5, 2)
+	for i, t2 := range cores {
+		t1 := prev[i]
+		all_delta := float64(t2.sum() - t1.sum())
+		if all_delta == 0 {
+			continue
+		}
+		percs[i] = CpuPercentages{
+			User:    Round(100*float64(t2.User-t1.User)/all_delta, .5, 2),
+			Nice:    Round(100*float64(t2.Nice-t1.Nice)/all_delta, .5, 2),
+			System:  Round(100*float64(t2.System-t1.System)/all_delta, .5, 2),
+			Idle:    Round(100*float64(t2.Idle-t1.Idle)/all_delta, .5, 2),
+			IOWait:  Round(100*float64(t2.IOWait-t1.IOWait)/all_delta, .5, 2),
+			Irq:     Round(100*float64(t2.Irq-t1.Irq)/all_delta, .5, 2),
+			SoftIrq: Round(100*float64(t2.SoftIrq-t1.SoftIrq)/all_delta, .5, 2),
+			Steal:   Round(100*float64(t2.Steal-t1.Steal)/all_delta, .5, 2),
+			Guest:   Round(100*float64(t2.Guest-t1.Guest)/all_delta, .5, 2),
+		}
+	}
+
+	return percs
 }
 
 func (t *Topbeat) getProcCpuPercentage(proc *Process) float64 {
@@ -108,13 +217,40 @@ func (t *Topbeat) Init(config TopConfig, events chan common.MapStr) error {
 		t.period = 1 * time.Second
 	}
 	if config.Procs != nil {
-		t.procs = *config.Procs
+		t.procsConfig = config.Procs
 	} else {
-		t.procs = []string{".*"} //all processes
+		allProcs := []string{".*"} //all processes
+		t.procsConfig = &ProcsConfig{Pattern: &allProcs}
+	}
+	t.finder = NewPIDFinder()
+
+	statsBackend := "gopsutil"
+	if config.StatsBackend != nil {
+		statsBackend = *config.StatsBackend
 	}
+	t.stats = NewStatsProvider(statsBackend)
+
+	if config.Devices != nil {
+		t.devices = *config.Devices
+	} else {
+		t.devices = []string{".*"} //all devices
+	}
+	if config.Interfaces != nil {
+		t.interfaces = *config.Interfaces
+	} else {
+		t.interfaces = []string{".*"} //all interfaces
+	}
+
+	t.lastDiskIOCounters = map[string]*DiskIOCounters{}
+	t.lastNetIOCounters = map[string]*NetIOCounters{}
+	t.lastCgroupStats = map[string]*CgroupStat{}
+	t.cgroupsConfig = config.Cgroups
 
 	logp.Debug("topbeat", "Init toppbeat")
-	logp.Debug("topbeat", "Follow processes %q\n", t.procs)
+	logp.Debug("topbeat", "Follow processes %+v\n", t.procsConfig)
+	logp.Debug("topbeat", "Follow devices %q\n", t.devices)
+	logp.Debug("topbeat", "Follow interfaces %q\n", t.interfaces)
+	logp.Debug("topbeat", "Stats backend %q\n", statsBackend)
 	logp.Debug("topbeat", "Period %v\n", t.period)
 	t.events = events
 	return nil
@@ -124,19 +260,12 @@ func (t *Topbeat) initProcStats() {
 
 	t.procsMap = make(ProcsMap)
 
-	if len(t.procs) == 0 {
-		return
-	}
-
-	pids, err := Pids()
-	if err != nil {
-		logp.Warn("Getting the list of pids: %v", err)
-	}
+	pids := t.matchedPids()
 
 	logp.Debug("topbeat", "Pids: %v\n", pids)
 
 	for _, pid := range pids {
-		process, err := GetProcess(pid)
+		process, err := t.stats.GetProcess(pid)
 		if err != nil {
 			logp.Debug("topbeat", "Skip process %d: %v", pid, err)
 			continue
@@ -147,69 +276,75 @@ func (t *Topbeat) initProcStats() {
 
 func (t *Topbeat) exportProcStats() error {
 
-	if len(t.procs) == 0 {
+	pids := t.matchedPids()
+	if len(pids) == 0 {
 		return nil
 	}
 
-	pids, err := Pids()
-	if err != nil {
-		logp.Warn("Getting the list of pids: %v", err)
-		return err
-	}
-
 	for _, pid := range pids {
-		process, err := GetProcess(pid)
+		process, err := t.stats.GetProcess(pid)
 		if err != nil {
 			logp.Debug("topbeat", "Skip process %d: %v", pid, err)
 			continue
 		}
 
-		if t.MatchProcess(process.Name) {
+		process.Cpu.UserPercent = t.getProcCpuPercentage(process)
+		process.Mem.RssPercent = t.getRssPercent(&process.Mem)
 
-			process.Cpu.UserPercent = t.getProcCpuPercentage(process)
-			process.Mem.RssPercent = t.getRssPercent(&process.Mem)
-
-			t.procsMap[process.Pid] = process
+		t.procsMap[process.Pid] = process
 
-			event := common.MapStr{
-				"timestamp":  common.Time(time.Now()),
-				"type":       "proc",
-				"proc.pid":   process.Pid,
-				"proc.ppid":  process.Ppid,
-				"proc.name":  process.Name,
-				"proc.state": process.State,
-				"proc.mem":   process.Mem,
-				"proc.cpu":   process.Cpu,
-			}
-			t.events <- event
+		event := common.MapStr{
+			"timestamp":  common.Time(time.Now()),
+			"type":       "proc",
+			"proc.pid":   process.Pid,
+			"proc.ppid":  process.Ppid,
+			"proc.name":  process.Name,
+			"proc.state": process.State,
+			"proc.mem":   process.Mem,
+			"proc.cpu":   process.Cpu,
+			"proc.io":    process.IO,
+			"proc.ext":   process.Ext,
 		}
+		t.events <- event
 	}
 	return nil
 }
 
 func (t *Topbeat) exportSystemStats() error {
 
-	load_stat, err := GetSystemLoad()
+	load_stat, err := t.stats.GetSystemLoad()
 	if err != nil {
 		logp.Warn("Getting load statistics: %v", err)
 		return err
 	}
-	cpu_stat, err := GetCpuTimes()
+	cpu_stat, err := t.stats.GetCpuTimes()
 	if err != nil {
 		logp.Warn("Getting cpu times: %v", err)
 		return err
 	}
 
-	cpu_stat.UserPercent = t.getCpuPercentage(cpu_stat)
+	cpu_stat.Percentages = t.getCpuPercentages(cpu_stat)
+
+	cores, err := GetPerCoreCpuTimes()
+	if err != nil {
+		logp.Warn("Getting per-core cpu times: %v", err)
+	}
+	corePercentages := t.getPerCoreCpuPercentages(cores)
 
-	mem_stat, err := GetMemory()
+	statCounters, err := GetStatCounters()
+	if err != nil {
+		logp.Warn("Getting /proc/stat counters: %v", err)
+		statCounters = &StatCounters{}
+	}
+
+	mem_stat, err := t.stats.GetMemory()
 	if err != nil {
 		logp.Warn("Getting memory details: %v", err)
 		return err
 	}
 	mem_stat.UsedPercent = t.getUsedMemPercent(mem_stat)
 
-	swap_stat, err := GetSwap()
+	swap_stat, err := t.stats.GetSwap()
 	if err != nil {
 		logp.Warn("Getting swap details: %v", err)
 		return err
@@ -217,13 +352,28 @@ func (t *Topbeat) exportSystemStats() error {
 	// calculate swap usage in percent
 	swap_stat.UsedPercent = t.getUsedMemPercent(swap_stat)
 
+	uptime_stat, err := GetUptime()
+	if err != nil {
+		logp.Warn("Getting uptime: %v", err)
+		return err
+	}
+
 	event := common.MapStr{
-		"timestamp": common.Time(time.Now()),
-		"type":      "system",
-		"load":      load_stat,
-		"cpu":       cpu_stat,
-		"mem":       mem_stat,
-		"swap":      swap_stat,
+		"timestamp":          common.Time(time.Now()),
+		"type":               "system",
+		"load":               load_stat,
+		"cpu":                cpu_stat,
+		"cpu.cores":          corePercentages,
+		"mem":                mem_stat,
+		"swap":               swap_stat,
+		"uptime.duration_ms": int64(uptime_stat.Duration / time.Millisecond),
+		"uptime.format":      uptime_stat.Format(),
+		"boot_time":          common.Time(uptime_stat.BootTime),
+		"ctxt":               statCounters.Ctxt,
+		"intr":               statCounters.Intr,
+		"processes":          statCounters.Processes,
+		"procs_running":      statCounters.ProcsRunning,
+		"procs_blocked":      statCounters.ProcsBlocked,
 	}
 
 	t.events <- event
@@ -231,6 +381,191 @@ func (t *Topbeat) exportSystemStats() error {
 	return nil
 }
 
+func (t *Topbeat) getDiskIORates(name string, counters *DiskIOCounters) (readBytesPerSec, writeBytesPerSec float64) {
+
+	prev, ok := t.lastDiskIOCounters[name]
+	t.lastDiskIOCounters[name] = counters
+	if !ok {
+		return 0, 0
+	}
+
+	seconds := t.period.Seconds()
+	readBytesPerSec = Round(float64(counters.ReadBytes-prev.ReadBytes)/seconds, .5, 2)
+	writeBytesPerSec = Round(float64(counters.WriteBytes-prev.WriteBytes)/seconds, .5, 2)
+	return readBytesPerSec, writeBytesPerSec
+}
+
+func (t *Topbeat) exportDiskIOStats() error {
+
+	devices, err := t.stats.GetDiskIO()
+	if err != nil {
+		logp.Warn("Getting disk IO stats: %v", err)
+		return err
+	}
+
+	for name, counters := range devices {
+		if !matchAny(t.devices, name) {
+			continue
+		}
+
+		readBytesPerSec, writeBytesPerSec := t.getDiskIORates(name, counters)
+
+		event := common.MapStr{
+			"timestamp":              common.Time(time.Now()),
+			"type":                   "diskio",
+			"diskio.name":            counters.Name,
+			"diskio.read_count":      counters.ReadCount,
+			"diskio.write_count":     counters.WriteCount,
+			"diskio.read_bytes":      counters.ReadBytes,
+			"diskio.write_bytes":     counters.WriteBytes,
+			"diskio.read_bytes_sec":  readBytesPerSec,
+			"diskio.write_bytes_sec": writeBytesPerSec,
+		}
+		t.events <- event
+	}
+
+	return nil
+}
+
+func (t *Topbeat) getNetIORates(name string, counters *NetIOCounters) (rxBytesPerSec, txBytesPerSec float64) {
+
+	prev, ok := t.lastNetIOCounters[name]
+	t.lastNetIOCounters[name] = counters
+	if !ok {
+		return 0, 0
+	}
+
+	seconds := t.period.Seconds()
+	rxBytesPerSec = Round(float64(counters.BytesRecv-prev.BytesRecv)/seconds, .5, 2)
+	txBytesPerSec = Round(float64(counters.BytesSent-prev.BytesSent)/seconds, .5, 2)
+	return rxBytesPerSec, txBytesPerSec
+}
+
+func (t *Topbeat) exportNetIOStats() error {
+
+	interfaces, err := t.stats.GetNetIO()
+	if err != nil {
+		logp.Warn("Getting network IO stats: %v", err)
+		return err
+	}
+
+	for name, counters := range interfaces {
+		if !matchAny(t.interfaces, name) {
+			continue
+		}
+
+		rxBytesPerSec, txBytesPerSec := t.getNetIORates(name, counters)
+
+		event := common.MapStr{
+			"timestamp":           common.Time(time.Now()),
+			"type":                "netio",
+			"netio.name":          counters.Name,
+			"netio.rx_bytes":      counters.BytesRecv,
+			"netio.rx_packets":    counters.PacketsRecv,
+			"netio.rx_errors":     counters.ErrIn,
+			"netio.rx_dropped":    counters.DropIn,
+			"netio.tx_bytes":      counters.BytesSent,
+			"netio.tx_packets":    counters.PacketsSent,
+			"netio.tx_errors":     counters.ErrOut,
+			"netio.tx_dropped":    counters.DropOut,
+			"netio.rx_bytes_sec":  rxBytesPerSec,
+			"netio.tx_bytes_sec":  txBytesPerSec,
+		}
+		t.events <- event
+	}
+
+	return nil
+}
+
+func (t *Topbeat) getCgroupCpuPercentage(path string, cpu CgroupCpuStat) float64 {
+
+	prev, ok := t.lastCgroupStats[path]
+	t.lastCgroupStats[path] = &CgroupStat{Path: path, Cpu: cpu}
+	if !ok {
+		return 0
+	}
+
+	quotaCores := float64(runtime.NumCPU())
+	if cpu.QuotaUs > 0 && cpu.PeriodUs > 0 {
+		quotaCores = float64(cpu.QuotaUs) / float64(cpu.PeriodUs)
+	}
+
+	deltaSeconds := float64(cpu.UsageNanos-prev.Cpu.UsageNanos) / 1e9
+	perc := deltaSeconds / (t.period.Seconds() * quotaCores) * 100
+	return Round(perc, .5, 2)
+}
+
+// cgroupPaths resolves the set of cgroup paths that should be reported on:
+// either every cgroup under Cgroups.Parent, or just the cgroups of the
+// currently tracked processes.
+func (t *Topbeat) cgroupPaths() []string {
+
+	if t.cgroupsConfig.EnumerateAll != nil && *t.cgroupsConfig.EnumerateAll {
+		paths, err := EnumerateCgroups(t.cgroupRoot(), t.cgroupParent())
+		if err != nil {
+			logp.Warn("Enumerating cgroups: %v", err)
+		}
+		return paths
+	}
+
+	seen := map[string]bool{}
+	var paths []string
+	for _, pid := range t.matchedPids() {
+		path, err := CgroupPathForPid(pid)
+		if err != nil || seen[path] {
+			continue
+		}
+		seen[path] = true
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+func (t *Topbeat) cgroupRoot() string {
+	if t.cgroupsConfig.Root != nil {
+		return *t.cgroupsConfig.Root
+	}
+	return defaultCgroupRoot
+}
+
+func (t *Topbeat) cgroupParent() string {
+	if t.cgroupsConfig.Parent != nil {
+		return *t.cgroupsConfig.Parent
+	}
+	return ""
+}
+
+func (t *Topbeat) exportCgroupStats() error {
+
+	if t.cgroupsConfig == nil {
+		return nil
+	}
+
+	root := t.cgroupRoot()
+
+	for _, path := range t.cgroupPaths() {
+		stat, err := ReadCgroupStats(root, path)
+		if err != nil {
+			logp.Debug("topbeat", "Skip cgroup %s: %v", path, err)
+			continue
+		}
+
+		stat.Cpu.UsagePercent = t.getCgroupCpuPercentage(path, stat.Cpu)
+
+		event := common.MapStr{
+			"timestamp":    common.Time(time.Now()),
+			"type":         "cgroup",
+			"cgroup.path":  stat.Path,
+			"cgroup.cpu":   stat.Cpu,
+			"cgroup.mem":   stat.Mem,
+			"cgroup.blkio": stat.Blkio,
+		}
+		t.events <- event
+	}
+
+	return nil
+}
+
 func (t *Topbeat) procCpuPercent(proc *Process) float64 {
 
 	oproc, ok := t.procsMap[proc.Pid]
@@ -258,6 +593,9 @@ func (t *Topbeat) Run() error {
 
 		t.exportSystemStats()
 		t.exportProcStats()
+		t.exportDiskIOStats()
+		t.exportNetIOStats()
+		t.exportCgroupStats()
 	}
 	return nil
 }
@@ -0,0 +1,15 @@
+package main
+
+// NetIOCounters holds the cumulative per-interface network I/O counters,
+// however they were sourced on the current platform.
+type NetIOCounters struct {
+	Name        string
+	BytesRecv   uint64
+	PacketsRecv uint64
+	ErrIn       uint64
+	DropIn      uint64
+	BytesSent   uint64
+	PacketsSent uint64
+	ErrOut      uint64
+	DropOut     uint64
+}
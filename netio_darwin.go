@@ -0,0 +1,82 @@
+package main
+
+import (
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// GetNetIO shells out to `netstat -ib`, which reports cumulative per-interface
+// counters on Darwin. netstat emits one row per address family bound to an
+// interface; we keep the first (link-layer) row we see for each name, since
+// that's the one carrying real byte/packet counts.
+func GetNetIO() (map[string]*NetIOCounters, error) {
+	out, err := exec.Command("netstat", "-ib").Output()
+	if err != nil {
+		return nil, err
+	}
+
+	counters := map[string]*NetIOCounters{}
+
+	lines := strings.Split(string(out), "\n")
+	if len(lines) == 0 {
+		return counters, nil
+	}
+
+	// header: Name  Mtu   Network       Address            Ipkts Ierrs     Ibytes    Opkts Oerrs     Obytes  Coll
+	header := strings.Fields(lines[0])
+	col := func(name string) int {
+		for i, h := range header {
+			if h == name {
+				return i
+			}
+		}
+		return -1
+	}
+	nameCol := col("Name")
+	ipktsCol := col("Ipkts")
+	ierrsCol := col("Ierrs")
+	ibytesCol := col("Ibytes")
+	opktsCol := col("Opkts")
+	oerrsCol := col("Oerrs")
+	obytesCol := col("Obytes")
+	if nameCol < 0 || ibytesCol < 0 || obytesCol < 0 {
+		return counters, nil
+	}
+
+	for _, line := range lines[1:] {
+		fields := strings.Fields(line)
+		if len(fields) <= ibytesCol || len(fields) <= obytesCol {
+			continue
+		}
+
+		name := fields[nameCol]
+		if _, seen := counters[name]; seen {
+			continue
+		}
+
+		ibytes, _ := strconv.ParseUint(fields[ibytesCol], 10, 64)
+		obytes, _ := strconv.ParseUint(fields[obytesCol], 10, 64)
+
+		c := &NetIOCounters{
+			Name:      name,
+			BytesRecv: ibytes,
+			BytesSent: obytes,
+		}
+		if ipktsCol >= 0 && len(fields) > ipktsCol {
+			c.PacketsRecv, _ = strconv.ParseUint(fields[ipktsCol], 10, 64)
+		}
+		if ierrsCol >= 0 && len(fields) > ierrsCol {
+			c.ErrIn, _ = strconv.ParseUint(fields[ierrsCol], 10, 64)
+		}
+		if opktsCol >= 0 && len(fields) > opktsCol {
+			c.PacketsSent, _ = strconv.ParseUint(fields[opktsCol], 10, 64)
+		}
+		if oerrsCol >= 0 && len(fields) > oerrsCol {
+			c.ErrOut, _ = strconv.ParseUint(fields[oerrsCol], 10, 64)
+		}
+		counters[name] = c
+	}
+
+	return counters, nil
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// GetNetIO reads /proc/net/dev and returns the cumulative I/O counters for
+// every network interface found there.
+func GetNetIO() (map[string]*NetIOCounters, error) {
+
+	file, err := os.Open("/proc/net/dev")
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return parseNetDev(file)
+}
+
+// parseNetDev parses the /proc/net/dev format out of r.
+func parseNetDev(r io.Reader) (map[string]*NetIOCounters, error) {
+
+	counters := map[string]*NetIOCounters{}
+
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		// the first two lines are headers
+		if lineNum <= 2 {
+			continue
+		}
+
+		parts := strings.SplitN(scanner.Text(), ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		name := strings.TrimSpace(parts[0])
+		fields := strings.Fields(parts[1])
+		// Receive: bytes packets errs drop fifo frame compressed multicast
+		// Transmit: bytes packets errs drop fifo colls carrier compressed
+		if len(fields) < 16 {
+			continue
+		}
+
+		recvBytes, _ := strconv.ParseUint(fields[0], 10, 64)
+		recvPackets, _ := strconv.ParseUint(fields[1], 10, 64)
+		recvErrs, _ := strconv.ParseUint(fields[2], 10, 64)
+		recvDrop, _ := strconv.ParseUint(fields[3], 10, 64)
+		sentBytes, _ := strconv.ParseUint(fields[8], 10, 64)
+		sentPackets, _ := strconv.ParseUint(fields[9], 10, 64)
+		sentErrs, _ := strconv.ParseUint(fields[10], 10, 64)
+		sentDrop, _ := strconv.ParseUint(fields[11], 10, 64)
+
+		counters[name] = &NetIOCounters{
+			Name:        name,
+			BytesRecv:   recvBytes,
+			PacketsRecv: recvPackets,
+			ErrIn:       recvErrs,
+			DropIn:      recvDrop,
+			BytesSent:   sentBytes,
+			PacketsSent: sentPackets,
+			ErrOut:      sentErrs,
+			DropOut:     sentDrop,
+		}
+	}
+
+	return counters, scanner.Err()
+}
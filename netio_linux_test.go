@@ -0,0 +1,61 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+const sampleNetDev = `Inter-|   Receive                                                |  Transmit
+ face |bytes    packets errs drop fifo frame compressed multicast|bytes    packets errs drop fifo colls carrier compressed
+    lo: 1000      10    0    0    0     0          0         0    1000      10    0    0    0     0       0          0
+  eth0: 50000    400    2    1    0     0          0         0    20000    300    1    0    0     0       0          0
+`
+
+func TestParseNetDev(t *testing.T) {
+
+	counters, err := parseNetDev(strings.NewReader(sampleNetDev))
+	if err != nil {
+		t.Fatalf("parseNetDev returned error: %v", err)
+	}
+
+	eth0, ok := counters["eth0"]
+	if !ok {
+		t.Fatalf("expected a counter for eth0, got %v", counters)
+	}
+	if eth0.BytesRecv != 50000 {
+		t.Errorf("BytesRecv = %d, want 50000", eth0.BytesRecv)
+	}
+	if eth0.PacketsRecv != 400 {
+		t.Errorf("PacketsRecv = %d, want 400", eth0.PacketsRecv)
+	}
+	if eth0.ErrIn != 2 {
+		t.Errorf("ErrIn = %d, want 2", eth0.ErrIn)
+	}
+	if eth0.DropIn != 1 {
+		t.Errorf("DropIn = %d, want 1", eth0.DropIn)
+	}
+	if eth0.BytesSent != 20000 {
+		t.Errorf("BytesSent = %d, want 20000", eth0.BytesSent)
+	}
+	if eth0.PacketsSent != 300 {
+		t.Errorf("PacketsSent = %d, want 300", eth0.PacketsSent)
+	}
+	if eth0.ErrOut != 1 {
+		t.Errorf("ErrOut = %d, want 1", eth0.ErrOut)
+	}
+
+	if _, ok := counters["lo"]; !ok {
+		t.Errorf("expected a counter for lo, got %v", counters)
+	}
+}
+
+func TestParseNetDevSkipsShortLines(t *testing.T) {
+
+	counters, err := parseNetDev(strings.NewReader("Inter-|   Receive\n face |bytes\n  eth0: 1 2 3\n"))
+	if err != nil {
+		t.Fatalf("parseNetDev returned error: %v", err)
+	}
+	if len(counters) != 0 {
+		t.Errorf("expected no counters from a malformed line, got %v", counters)
+	}
+}
@@ -0,0 +1,9 @@
+package main
+
+// GetNetIO is not yet implemented on Windows: per-interface byte/packet
+// counters require the IP Helper API (GetIfTable2), which needs more
+// plumbing than a stub should take on. Rather than leave the package
+// unbuildable on Windows, report no interfaces until that's wired up.
+func GetNetIO() (map[string]*NetIOCounters, error) {
+	return map[string]*NetIOCounters{}, nil
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GetUptime shells out to sysctl kern.boottime, which reports the system
+// boot time as a struct timeval formatted like "{ sec = 1690000000, usec =
+// 123456 }", and derives the running duration from it.
+func GetUptime() (*UptimeStat, error) {
+
+	out, err := exec.Command("sysctl", "-n", "kern.boottime").Output()
+	if err != nil {
+		return nil, err
+	}
+
+	fields := strings.Fields(string(out))
+	// { sec = 1690000000, usec = 123456 }
+	var sec int64
+	for i, f := range fields {
+		if f == "sec" && i+2 < len(fields) {
+			sec, err = strconv.ParseInt(strings.TrimSuffix(fields[i+2], ","), 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+	if sec == 0 {
+		return nil, fmt.Errorf("unexpected format in kern.boottime: %q", out)
+	}
+
+	bootTime := time.Unix(sec, 0)
+
+	return &UptimeStat{
+		Duration: time.Since(bootTime),
+		BootTime: bootTime,
+	}, nil
+}
+
+// GetPerCoreCpuTimes is not yet implemented on Darwin: per-core jiffy-style
+// counters require the host_processor_info Mach call, which needs cgo.
+// Rather than leave the package unbuildable on Darwin, report no cores
+// until that's wired up.
+func GetPerCoreCpuTimes() ([]*CpuTimes, error) {
+	return nil, nil
+}
+
+// GetStatCounters is not yet implemented on Darwin: there is no equivalent
+// of /proc/stat's ctxt/intr/processes counters without sysctl calls this
+// package doesn't make yet. Rather than leave the package unbuildable on
+// Darwin, report zero-valued counters until that's wired up.
+func GetStatCounters() (*StatCounters, error) {
+	return &StatCounters{}, nil
+}
@@ -0,0 +1,386 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Pids returns the list of process ids currently running on the system, by
+// listing the numeric entries of /proc.
+func Pids() ([]int, error) {
+
+	dir, err := os.Open("/proc")
+	if err != nil {
+		return nil, err
+	}
+	defer dir.Close()
+
+	names, err := dir.Readdirnames(0)
+	if err != nil {
+		return nil, err
+	}
+
+	pids := []int{}
+	for _, name := range names {
+		pid, err := strconv.Atoi(name)
+		if err != nil {
+			continue
+		}
+		pids = append(pids, pid)
+	}
+
+	return pids, nil
+}
+
+// GetProcess reads /proc/<pid>/stat, /proc/<pid>/statm and /proc/<pid>/io
+// to build a Process snapshot.
+func GetProcess(pid int) (*Process, error) {
+
+	stat, err := ioutil.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return nil, err
+	}
+
+	// the process name is surrounded by parenthesis and may contain spaces,
+	// so look it up between the first '(' and the last ')'.
+	nameStart := strings.IndexByte(string(stat), '(')
+	nameEnd := strings.LastIndexByte(string(stat), ')')
+	if nameStart < 0 || nameEnd < 0 || nameEnd < nameStart {
+		return nil, fmt.Errorf("unexpected format in /proc/%d/stat", pid)
+	}
+	name := string(stat)[nameStart+1 : nameEnd]
+	fields := strings.Fields(string(stat)[nameEnd+2:])
+
+	proc := &Process{
+		Pid:   pid,
+		Name:  name,
+		ctime: time.Now(),
+	}
+
+	if len(fields) > 1 {
+		proc.State = fields[0]
+	}
+	if len(fields) > 2 {
+		proc.Ppid, _ = strconv.Atoi(fields[1])
+	}
+	if len(fields) > 12 {
+		utime, _ := strconv.ParseUint(fields[11], 10, 64)
+		stime, _ := strconv.ParseUint(fields[12], 10, 64)
+		// convert clock ticks (usually 100/s) to milliseconds
+		proc.Cpu.User = utime * 10
+		proc.Cpu.System = stime * 10
+	}
+
+	statm, err := ioutil.ReadFile(fmt.Sprintf("/proc/%d/statm", pid))
+	if err == nil {
+		memFields := strings.Fields(string(statm))
+		if len(memFields) > 2 {
+			pageSize := uint64(os.Getpagesize())
+			size, _ := strconv.ParseUint(memFields[0], 10, 64)
+			rss, _ := strconv.ParseUint(memFields[1], 10, 64)
+			share, _ := strconv.ParseUint(memFields[2], 10, 64)
+			proc.Mem.Size = size * pageSize
+			proc.Mem.Rss = rss * pageSize
+			proc.Mem.Share = share * pageSize
+		}
+	}
+
+	readProcIO(pid, &proc.IO)
+
+	return proc, nil
+}
+
+// readProcIO fills in io with the counters found in /proc/<pid>/io. Older
+// kernels, or processes we don't have permission to inspect, simply don't
+// expose this file, so a missing file is not treated as an error.
+func readProcIO(pid int, io *ProcIOStat) {
+
+	content, err := ioutil.ReadFile(fmt.Sprintf("/proc/%d/io", pid))
+	if err != nil {
+		return
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(string(content)))
+	for scanner.Scan() {
+		parts := strings.SplitN(scanner.Text(), ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		value, err := strconv.ParseUint(strings.TrimSpace(parts[1]), 10, 64)
+		if err != nil {
+			continue
+		}
+		switch strings.TrimSpace(parts[0]) {
+		case "syscr":
+			io.ReadCount = value
+		case "syscw":
+			io.WriteCount = value
+		case "read_bytes":
+			io.ReadBytes = value
+		case "write_bytes":
+			io.WriteBytes = value
+		}
+	}
+}
+
+// GetMemory reads /proc/meminfo and returns the current memory usage.
+func GetMemory() (*MemStat, error) {
+
+	fields, err := parseMeminfo()
+	if err != nil {
+		return nil, err
+	}
+
+	mem := &MemStat{
+		Total: fields["MemTotal"] * 1024,
+		Free:  fields["MemFree"] * 1024,
+	}
+	mem.Used = mem.Total - mem.Free
+	mem.Actual.Free = (fields["MemFree"] + fields["Buffers"] + fields["Cached"]) * 1024
+	mem.Actual.Used = mem.Total - mem.Actual.Free
+
+	return mem, nil
+}
+
+// GetSwap reads /proc/meminfo and returns the current swap usage.
+func GetSwap() (*MemStat, error) {
+
+	fields, err := parseMeminfo()
+	if err != nil {
+		return nil, err
+	}
+
+	swap := &MemStat{
+		Total: fields["SwapTotal"] * 1024,
+		Free:  fields["SwapFree"] * 1024,
+	}
+	swap.Used = swap.Total - swap.Free
+
+	return swap, nil
+}
+
+func parseMeminfo() (map[string]uint64, error) {
+
+	file, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	fields := map[string]uint64{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		parts := strings.SplitN(scanner.Text(), ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		valueFields := strings.Fields(parts[1])
+		if len(valueFields) == 0 {
+			continue
+		}
+		value, err := strconv.ParseUint(valueFields[0], 10, 64)
+		if err != nil {
+			continue
+		}
+		fields[strings.TrimSpace(parts[0])] = value
+	}
+
+	return fields, scanner.Err()
+}
+
+// GetCpuTimes reads the aggregate "cpu" line of /proc/stat.
+func GetCpuTimes() (*CpuTimes, error) {
+
+	file, err := os.Open("/proc/stat")
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || fields[0] != "cpu" {
+			continue
+		}
+		return parseCpuTimesFields(fields[1:])
+	}
+
+	return nil, fmt.Errorf("cpu line not found in /proc/stat")
+}
+
+func parseCpuTimesFields(fields []string) (*CpuTimes, error) {
+
+	numbers := make([]uint64, len(fields))
+	for i, f := range fields {
+		n, err := strconv.ParseUint(f, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		numbers[i] = n
+	}
+
+	t := &CpuTimes{}
+	if len(numbers) > 0 {
+		t.User = numbers[0]
+	}
+	if len(numbers) > 1 {
+		t.Nice = numbers[1]
+	}
+	if len(numbers) > 2 {
+		t.System = numbers[2]
+	}
+	if len(numbers) > 3 {
+		t.Idle = numbers[3]
+	}
+	if len(numbers) > 4 {
+		t.IOWait = numbers[4]
+	}
+	if len(numbers) > 5 {
+		t.Irq = numbers[5]
+	}
+	if len(numbers) > 6 {
+		t.SoftIrq = numbers[6]
+	}
+	if len(numbers) > 7 {
+		t.Steal = numbers[7]
+	}
+	if len(numbers) > 8 {
+		t.Guest = numbers[8]
+	}
+
+	return t, nil
+}
+
+// GetPerCoreCpuTimes reads every "cpuN" line of /proc/stat and returns the
+// per-core jiffy counts, ordered by core number.
+func GetPerCoreCpuTimes() ([]*CpuTimes, error) {
+
+	file, err := os.Open("/proc/stat")
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var cores []*CpuTimes
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || !strings.HasPrefix(fields[0], "cpu") || fields[0] == "cpu" {
+			continue
+		}
+		if _, err := strconv.Atoi(strings.TrimPrefix(fields[0], "cpu")); err != nil {
+			continue
+		}
+
+		t, err := parseCpuTimesFields(fields[1:])
+		if err != nil {
+			return nil, err
+		}
+		cores = append(cores, t)
+	}
+
+	return cores, scanner.Err()
+}
+
+// GetStatCounters reads the scheduler counters ("ctxt", "intr", "processes",
+// "procs_running", "procs_blocked") of /proc/stat.
+func GetStatCounters() (*StatCounters, error) {
+
+	file, err := os.Open("/proc/stat")
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	counters := &StatCounters{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+
+		value, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+
+		switch fields[0] {
+		case "ctxt":
+			counters.Ctxt = value
+		case "intr":
+			counters.Intr = value
+		case "processes":
+			counters.Processes = value
+		case "procs_running":
+			counters.ProcsRunning = value
+		case "procs_blocked":
+			counters.ProcsBlocked = value
+		}
+	}
+
+	return counters, scanner.Err()
+}
+
+// GetUptime reads /proc/uptime and returns how long the system has been
+// running, as well as the time it booted.
+func GetUptime() (*UptimeStat, error) {
+
+	content, err := ioutil.ReadFile("/proc/uptime")
+	if err != nil {
+		return nil, err
+	}
+
+	fields := strings.Fields(string(content))
+	if len(fields) < 1 {
+		return nil, fmt.Errorf("unexpected format in /proc/uptime")
+	}
+
+	seconds, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return nil, err
+	}
+
+	duration := time.Duration(seconds * float64(time.Second))
+
+	return &UptimeStat{
+		Duration: duration,
+		BootTime: time.Now().Add(-duration),
+	}, nil
+}
+
+// GetSystemLoad reads /proc/loadavg.
+func GetSystemLoad() (*LoadStat, error) {
+
+	content, err := ioutil.ReadFile("/proc/loadavg")
+	if err != nil {
+		return nil, err
+	}
+
+	fields := strings.Fields(string(content))
+	if len(fields) < 3 {
+		return nil, fmt.Errorf("unexpected format in /proc/loadavg")
+	}
+
+	load := &LoadStat{}
+	load.Load1, err = strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return nil, err
+	}
+	load.Load5, err = strconv.ParseFloat(fields[1], 64)
+	if err != nil {
+		return nil, err
+	}
+	load.Load15, err = strconv.ParseFloat(fields[2], 64)
+	if err != nil {
+		return nil, err
+	}
+
+	return load, nil
+}
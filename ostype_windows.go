@@ -0,0 +1,41 @@
+package main
+
+import (
+	"syscall"
+	"time"
+)
+
+var (
+	modkernel32        = syscall.NewLazyDLL("kernel32.dll")
+	procGetTickCount64 = modkernel32.NewProc("GetTickCount64")
+)
+
+// GetUptime calls GetTickCount64, which returns the number of milliseconds
+// since the system started, and derives the boot time from it.
+func GetUptime() (*UptimeStat, error) {
+
+	r1, _, _ := procGetTickCount64.Call()
+	duration := time.Duration(r1) * time.Millisecond
+
+	return &UptimeStat{
+		Duration: duration,
+		BootTime: time.Now().Add(-duration),
+	}, nil
+}
+
+// GetPerCoreCpuTimes is not yet implemented on Windows: per-core counters
+// require the PDH "Processor" counters, which needs more plumbing than a
+// stub should take on. Rather than leave the package unbuildable on
+// Windows, report no cores until that's wired up.
+func GetPerCoreCpuTimes() ([]*CpuTimes, error) {
+	return nil, nil
+}
+
+// GetStatCounters is not yet implemented on Windows: there is no
+// equivalent of /proc/stat's ctxt/intr/processes counters without
+// additional Windows API calls this package doesn't make yet. Rather than
+// leave the package unbuildable on Windows, report zero-valued counters
+// until that's wired up.
+func GetStatCounters() (*StatCounters, error) {
+	return &StatCounters{}, nil
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"os/user"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// PIDFinder locates the process ids matching a selector. Several selectors
+// exist because a single regex on the short process name (as reported by
+// the kernel) is not enough to reliably identify a process: a JVM, for
+// instance, is always just named "java".
+type PIDFinder interface {
+	// Pattern returns the pids whose short process name matches expr.
+	Pattern(expr string) ([]int, error)
+	// FullPattern returns the pids whose full command line matches expr.
+	FullPattern(expr string) ([]int, error)
+	// PidFile reads a pid from the given pidfile and returns it if the
+	// process is still alive.
+	PidFile(path string) ([]int, error)
+	// Exe returns the pids whose executable path matches expr.
+	Exe(expr string) ([]int, error)
+	// Uid returns the pids owned by the given user.
+	Uid(user string) ([]int, error)
+}
+
+// pgrepFinder is a PIDFinder implementation that shells out to pgrep. It is
+// used on platforms where we don't have a native implementation of process
+// introspection.
+type pgrepFinder struct{}
+
+func (f *pgrepFinder) Pattern(expr string) ([]int, error) {
+	// without -f, pgrep matches against the short process name only,
+	// keeping parity with nativeFinder.Pattern.
+	return runPgrep(expr)
+}
+
+func (f *pgrepFinder) FullPattern(expr string) ([]int, error) {
+	return runPgrep("-f", expr)
+}
+
+func (f *pgrepFinder) PidFile(path string) ([]int, error) {
+	return readPidFile(path)
+}
+
+func (f *pgrepFinder) Exe(expr string) ([]int, error) {
+	// pgrep matches against the full command line, which in practice
+	// starts with the executable path, so this is a reasonable
+	// approximation of matching against argv[0]/exe.
+	return runPgrep("-f", "^"+expr)
+}
+
+func (f *pgrepFinder) Uid(user string) ([]int, error) {
+	return runPgrep("-u", user)
+}
+
+// runPgrep invokes pgrep with the given arguments and parses its stdout,
+// one pid per line. pgrep exits with status 1 (and no output) when nothing
+// matches, which is not treated as an error.
+func runPgrep(args ...string) ([]int, error) {
+
+	out, err := exec.Command("pgrep", args...).Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && len(out) == 0 {
+			if status, ok := exitErr.Sys().(syscall.WaitStatus); ok && status.ExitStatus() == 1 {
+				return nil, nil
+			}
+		}
+		return nil, err
+	}
+
+	var pids []int
+	for _, line := range strings.Fields(string(out)) {
+		pid, err := strconv.Atoi(line)
+		if err != nil {
+			continue
+		}
+		pids = append(pids, pid)
+	}
+	return pids, nil
+}
+
+// readPidFile reads a single pid from path and returns it, as a one-element
+// slice, if the process is still alive. It is shared by the PIDFinder
+// implementations since pidfiles are parsed the same way everywhere.
+func readPidFile(path string) ([]int, error) {
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	pid, err := strconv.Atoi(strings.TrimSpace(string(content)))
+	if err != nil {
+		return nil, err
+	}
+
+	// a pid is considered alive if sending it signal 0 succeeds.
+	if !processExists(pid) {
+		return nil, nil
+	}
+
+	return []int{pid}, nil
+}
+
+// lookupUid resolves a user name (or numeric uid given as a string) to its
+// numeric uid.
+func lookupUid(name string) (int, error) {
+
+	u, err := user.Lookup(name)
+	if err != nil {
+		if id, idErr := strconv.Atoi(name); idErr == nil {
+			return id, nil
+		}
+		return 0, err
+	}
+
+	return strconv.Atoi(u.Uid)
+}
+
+// processExists reports whether a process with the given pid is currently
+// running.
+func processExists(pid int) bool {
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	return proc.Signal(syscall.Signal(0)) == nil
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// nativeFinder is a PIDFinder implementation that walks /proc directly,
+// without shelling out to external tools.
+type nativeFinder struct{}
+
+// NewPIDFinder returns the preferred PIDFinder implementation for this
+// platform.
+func NewPIDFinder() PIDFinder {
+	return &nativeFinder{}
+}
+
+func (f *nativeFinder) Pattern(expr string) ([]int, error) {
+	return f.matching(expr, func(pid int) (string, error) {
+		proc, err := GetProcess(pid)
+		if err != nil {
+			return "", err
+		}
+		return proc.Name, nil
+	})
+}
+
+func (f *nativeFinder) FullPattern(expr string) ([]int, error) {
+	return f.matching(expr, func(pid int) (string, error) {
+		content, err := ioutil.ReadFile(fmt.Sprintf("/proc/%d/cmdline", pid))
+		if err != nil {
+			return "", err
+		}
+		// cmdline args are NUL-separated
+		return strings.Join(strings.Split(strings.TrimRight(string(content), "\x00"), "\x00"), " "), nil
+	})
+}
+
+func (f *nativeFinder) Exe(expr string) ([]int, error) {
+	return f.matching(expr, func(pid int) (string, error) {
+		return os.Readlink(fmt.Sprintf("/proc/%d/exe", pid))
+	})
+}
+
+func (f *nativeFinder) PidFile(path string) ([]int, error) {
+	return readPidFile(path)
+}
+
+func (f *nativeFinder) Uid(user string) ([]int, error) {
+
+	uid, err := lookupUid(user)
+	if err != nil {
+		return nil, err
+	}
+
+	pids, err := Pids()
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []int
+	for _, pid := range pids {
+		procUid, err := readProcUid(pid)
+		if err != nil {
+			continue
+		}
+		if procUid == uid {
+			matched = append(matched, pid)
+		}
+	}
+	return matched, nil
+}
+
+// matching lists all pids and returns the ones for which value(pid) matches
+// expr.
+func (f *nativeFinder) matching(expr string, value func(pid int) (string, error)) ([]int, error) {
+
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	pids, err := Pids()
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []int
+	for _, pid := range pids {
+		s, err := value(pid)
+		if err != nil {
+			continue
+		}
+		if re.MatchString(s) {
+			matched = append(matched, pid)
+		}
+	}
+	return matched, nil
+}
+
+// readProcUid reads the real uid of a process from /proc/<pid>/status.
+func readProcUid(pid int) (int, error) {
+
+	content, err := ioutil.ReadFile(fmt.Sprintf("/proc/%d/status", pid))
+	if err != nil {
+		return 0, err
+	}
+
+	for _, line := range strings.Split(string(content), "\n") {
+		if !strings.HasPrefix(line, "Uid:") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			break
+		}
+		return strconv.Atoi(fields[1])
+	}
+
+	return 0, fmt.Errorf("Uid not found in /proc/%d/status", pid)
+}
@@ -0,0 +1,10 @@
+// +build !linux
+
+package main
+
+// NewPIDFinder returns the preferred PIDFinder implementation for this
+// platform. We don't yet have a native process walker outside of Linux, so
+// fall back to shelling out to pgrep.
+func NewPIDFinder() PIDFinder {
+	return &pgrepFinder{}
+}
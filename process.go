@@ -0,0 +1,61 @@
+package main
+
+import "time"
+
+// ProcMemStat holds per-process memory usage, in bytes.
+type ProcMemStat struct {
+	Size       uint64  `json:"size"`
+	Rss        uint64  `json:"rss"`
+	RssPercent float64 `json:"rss_p"`
+	Share      uint64  `json:"share"`
+}
+
+// ProcCpuTime holds per-process cpu time, in milliseconds, as well as the
+// percentage of cpu time used since the last sample.
+type ProcCpuTime struct {
+	User        uint64  `json:"user"`
+	System      uint64  `json:"system"`
+	UserPercent float64 `json:"user_p"`
+}
+
+// ProcIOStat holds the per-process I/O counters exposed by the kernel in
+// /proc/<pid>/io, letting a heavy-IO process be correlated with the
+// device-level diskio stats.
+type ProcIOStat struct {
+	ReadCount  uint64 `json:"read_count"`
+	WriteCount uint64 `json:"write_count"`
+	ReadBytes  uint64 `json:"read_bytes"`
+	WriteBytes uint64 `json:"write_bytes"`
+}
+
+// ProcExtendedStat holds additional per-process detail that only the
+// gopsutil-backed StatsProvider can supply; the native /proc-only backend
+// leaves it zero-valued.
+type ProcExtendedStat struct {
+	NumThreads          int32   `json:"num_threads"`
+	NumFDs              int32   `json:"num_fds"`
+	NumCtxSwitchesVol   int64   `json:"num_ctx_switches_voluntary"`
+	NumCtxSwitchesInvol int64   `json:"num_ctx_switches_involuntary"`
+	Nice                int32   `json:"nice"`
+	IONice              int32   `json:"ionice"`
+	Uids                []int32 `json:"uids"`
+	Gids                []int32 `json:"gids"`
+	CreateTime          int64   `json:"create_time"`
+}
+
+// Process is a snapshot of a single process, as read from the OS at a given
+// point in time.
+type Process struct {
+	Pid   int
+	Ppid  int
+	Name  string
+	State string
+	Mem   ProcMemStat
+	Cpu   ProcCpuTime
+	IO    ProcIOStat
+	Ext   ProcExtendedStat
+
+	// ctime is the time at which this sample was taken, used to compute
+	// cpu percentages between two samples of the same pid.
+	ctime time.Time
+}
@@ -0,0 +1,27 @@
+package main
+
+// StatsProvider abstracts over how system and process statistics are
+// collected, so the same Topbeat code can run unchanged against either our
+// native /proc parsing or a portable backend such as gopsutil.
+type StatsProvider interface {
+	GetMemory() (*MemStat, error)
+	GetSwap() (*MemStat, error)
+	GetCpuTimes() (*CpuTimes, error)
+	GetSystemLoad() (*LoadStat, error)
+	Pids() ([]int, error)
+	GetProcess(pid int) (*Process, error)
+	GetDiskIO() (map[string]*DiskIOCounters, error)
+	GetNetIO() (map[string]*NetIOCounters, error)
+}
+
+// NewStatsProvider returns the StatsProvider selected by name, defaulting
+// to "gopsutil" (for its portability) when name is empty.
+func NewStatsProvider(name string) StatsProvider {
+
+	switch name {
+	case "native":
+		return newNativeStatsProvider()
+	default:
+		return &gopsutilStatsProvider{}
+	}
+}
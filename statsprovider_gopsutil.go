@@ -0,0 +1,204 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shirou/gopsutil/cpu"
+	"github.com/shirou/gopsutil/disk"
+	"github.com/shirou/gopsutil/load"
+	"github.com/shirou/gopsutil/mem"
+	"github.com/shirou/gopsutil/net"
+	"github.com/shirou/gopsutil/process"
+)
+
+// gopsutilStatsProvider implements StatsProvider on top of gopsutil. It
+// gains us Darwin/FreeBSD/Windows support for the same code path, plus
+// richer per-process detail (Process.Ext: NumThreads, NumFDs,
+// NumCtxSwitches, Nice, IONice, Uids/Gids, CreateTime) than the native
+// /proc-only implementation exposes.
+type gopsutilStatsProvider struct{}
+
+func (gopsutilStatsProvider) GetMemory() (*MemStat, error) {
+
+	vm, err := mem.VirtualMemory()
+	if err != nil {
+		return nil, err
+	}
+
+	m := &MemStat{
+		Total: vm.Total,
+		Used:  vm.Used,
+		Free:  vm.Free,
+	}
+	m.Actual.Free = vm.Available
+	m.Actual.Used = vm.Total - vm.Available
+	return m, nil
+}
+
+func (gopsutilStatsProvider) GetSwap() (*MemStat, error) {
+
+	sm, err := mem.SwapMemory()
+	if err != nil {
+		return nil, err
+	}
+
+	return &MemStat{Total: sm.Total, Used: sm.Used, Free: sm.Free}, nil
+}
+
+func (gopsutilStatsProvider) GetCpuTimes() (*CpuTimes, error) {
+
+	times, err := cpu.Times(false)
+	if err != nil {
+		return nil, err
+	}
+	if len(times) == 0 {
+		return nil, fmt.Errorf("gopsutil: no aggregate cpu times returned")
+	}
+
+	return cpuTimesFromGopsutil(times[0]), nil
+}
+
+// cpuTimesFromGopsutil converts gopsutil's times, in seconds, to ticks of
+// 1/100s (the usual kernel USER_HZ), so sub-second deltas are not lost.
+func cpuTimesFromGopsutil(t cpu.TimesStat) *CpuTimes {
+	return &CpuTimes{
+		User:    uint64(t.User * 100),
+		Nice:    uint64(t.Nice * 100),
+		System:  uint64(t.System * 100),
+		Idle:    uint64(t.Idle * 100),
+		IOWait:  uint64(t.Iowait * 100),
+		Irq:     uint64(t.Irq * 100),
+		SoftIrq: uint64(t.Softirq * 100),
+		Steal:   uint64(t.Steal * 100),
+	}
+}
+
+func (gopsutilStatsProvider) GetSystemLoad() (*LoadStat, error) {
+
+	avg, err := load.Avg()
+	if err != nil {
+		return nil, err
+	}
+
+	return &LoadStat{Load1: avg.Load1, Load5: avg.Load5, Load15: avg.Load15}, nil
+}
+
+func (gopsutilStatsProvider) Pids() ([]int, error) {
+
+	pids, err := process.Pids()
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, len(pids))
+	for i, pid := range pids {
+		ids[i] = int(pid)
+	}
+	return ids, nil
+}
+
+func (gopsutilStatsProvider) GetProcess(pid int) (*Process, error) {
+
+	p, err := process.NewProcess(int32(pid))
+	if err != nil {
+		return nil, err
+	}
+
+	proc := &Process{Pid: pid, ctime: time.Now()}
+
+	if name, err := p.Name(); err == nil {
+		proc.Name = name
+	}
+	if ppid, err := p.Ppid(); err == nil {
+		proc.Ppid = int(ppid)
+	}
+	if status, err := p.Status(); err == nil {
+		proc.State = status
+	}
+	if memInfo, err := p.MemoryInfo(); err == nil && memInfo != nil {
+		proc.Mem.Rss = memInfo.RSS
+		proc.Mem.Size = memInfo.VMS
+	}
+	if times, err := p.Times(); err == nil {
+		proc.Cpu.User = uint64(times.User * 1000)
+		proc.Cpu.System = uint64(times.System * 1000)
+	}
+	if ioCounters, err := p.IOCounters(); err == nil && ioCounters != nil {
+		proc.IO.ReadCount = ioCounters.ReadCount
+		proc.IO.WriteCount = ioCounters.WriteCount
+		proc.IO.ReadBytes = ioCounters.ReadBytes
+		proc.IO.WriteBytes = ioCounters.WriteBytes
+	}
+	if numThreads, err := p.NumThreads(); err == nil {
+		proc.Ext.NumThreads = numThreads
+	}
+	if numFDs, err := p.NumFDs(); err == nil {
+		proc.Ext.NumFDs = numFDs
+	}
+	if ctxSwitches, err := p.NumCtxSwitches(); err == nil && ctxSwitches != nil {
+		proc.Ext.NumCtxSwitchesVol = ctxSwitches.Voluntary
+		proc.Ext.NumCtxSwitchesInvol = ctxSwitches.Involuntary
+	}
+	if nice, err := p.Nice(); err == nil {
+		proc.Ext.Nice = nice
+	}
+	if ioNice, err := p.IOnice(); err == nil {
+		proc.Ext.IONice = ioNice
+	}
+	if uids, err := p.Uids(); err == nil {
+		proc.Ext.Uids = uids
+	}
+	if gids, err := p.Gids(); err == nil {
+		proc.Ext.Gids = gids
+	}
+	if createTime, err := p.CreateTime(); err == nil {
+		proc.Ext.CreateTime = createTime
+	}
+
+	return proc, nil
+}
+
+func (gopsutilStatsProvider) GetDiskIO() (map[string]*DiskIOCounters, error) {
+
+	counters, err := disk.IOCounters()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]*DiskIOCounters, len(counters))
+	for name, c := range counters {
+		result[name] = &DiskIOCounters{
+			Name:       name,
+			ReadCount:  c.ReadCount,
+			WriteCount: c.WriteCount,
+			ReadBytes:  c.ReadBytes,
+			WriteBytes: c.WriteBytes,
+		}
+	}
+	return result, nil
+}
+
+func (gopsutilStatsProvider) GetNetIO() (map[string]*NetIOCounters, error) {
+
+	counters, err := net.IOCounters(true)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]*NetIOCounters, len(counters))
+	for _, c := range counters {
+		result[c.Name] = &NetIOCounters{
+			Name:        c.Name,
+			BytesRecv:   c.BytesRecv,
+			PacketsRecv: c.PacketsRecv,
+			ErrIn:       c.Errin,
+			DropIn:      c.Dropin,
+			BytesSent:   c.BytesSent,
+			PacketsSent: c.PacketsSent,
+			ErrOut:      c.Errout,
+			DropOut:     c.Dropout,
+		}
+	}
+	return result, nil
+}
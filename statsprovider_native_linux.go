@@ -0,0 +1,29 @@
+package main
+
+// newNativeStatsProvider returns the "native" StatsProvider, backed by this
+// package's own /proc parsing.
+func newNativeStatsProvider() StatsProvider {
+	return &nativeStatsProvider{}
+}
+
+// nativeStatsProvider implements StatsProvider on top of this package's own
+// /proc parsing.
+type nativeStatsProvider struct{}
+
+func (nativeStatsProvider) GetMemory() (*MemStat, error)      { return GetMemory() }
+func (nativeStatsProvider) GetSwap() (*MemStat, error)        { return GetSwap() }
+func (nativeStatsProvider) GetCpuTimes() (*CpuTimes, error)   { return GetCpuTimes() }
+func (nativeStatsProvider) GetSystemLoad() (*LoadStat, error) { return GetSystemLoad() }
+func (nativeStatsProvider) Pids() ([]int, error)              { return Pids() }
+
+func (nativeStatsProvider) GetProcess(pid int) (*Process, error) {
+	return GetProcess(pid)
+}
+
+func (nativeStatsProvider) GetDiskIO() (map[string]*DiskIOCounters, error) {
+	return GetDiskIO()
+}
+
+func (nativeStatsProvider) GetNetIO() (map[string]*NetIOCounters, error) {
+	return GetNetIO()
+}
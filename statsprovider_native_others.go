@@ -0,0 +1,36 @@
+// +build !linux
+
+package main
+
+import "fmt"
+
+// newNativeStatsProvider returns the "native" StatsProvider. On non-Linux
+// platforms there is no /proc to parse, so it returns a stub that reports
+// every call as unsupported; use the gopsutil backend there instead.
+func newNativeStatsProvider() StatsProvider {
+	return &nativeStatsProvider{}
+}
+
+// nativeStatsProvider stubs out StatsProvider on platforms where we have no
+// native (/proc-based) implementation to back it with.
+type nativeStatsProvider struct{}
+
+var errNativeUnsupported = fmt.Errorf("native stats backend is not supported on this platform")
+
+func (nativeStatsProvider) GetMemory() (*MemStat, error)      { return nil, errNativeUnsupported }
+func (nativeStatsProvider) GetSwap() (*MemStat, error)        { return nil, errNativeUnsupported }
+func (nativeStatsProvider) GetCpuTimes() (*CpuTimes, error)   { return nil, errNativeUnsupported }
+func (nativeStatsProvider) GetSystemLoad() (*LoadStat, error) { return nil, errNativeUnsupported }
+func (nativeStatsProvider) Pids() ([]int, error)              { return nil, errNativeUnsupported }
+
+func (nativeStatsProvider) GetProcess(pid int) (*Process, error) {
+	return nil, errNativeUnsupported
+}
+
+func (nativeStatsProvider) GetDiskIO() (map[string]*DiskIOCounters, error) {
+	return nil, errNativeUnsupported
+}
+
+func (nativeStatsProvider) GetNetIO() (map[string]*NetIOCounters, error) {
+	return nil, errNativeUnsupported
+}
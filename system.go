@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// CpuTimes holds the cumulative jiffies/ticks spent in each CPU mode since
+// boot, as reported by the kernel. Deltas between two samples are used to
+// compute percentages.
+type CpuTimes struct {
+	User    uint64
+	Nice    uint64
+	System  uint64
+	Idle    uint64
+	IOWait  uint64
+	Irq     uint64
+	SoftIrq uint64
+	Steal   uint64
+	Guest   uint64
+
+	Percentages CpuPercentages `json:"percentages"`
+}
+
+// CpuPercentages holds, for each CPU mode, the share of a sampling
+// interval's total delta spent in that mode.
+type CpuPercentages struct {
+	User    float64 `json:"user_p"`
+	Nice    float64 `json:"nice_p"`
+	System  float64 `json:"system_p"`
+	Idle    float64 `json:"idle_p"`
+	IOWait  float64 `json:"iowait_p"`
+	Irq     float64 `json:"irq_p"`
+	SoftIrq float64 `json:"softirq_p"`
+	Steal   float64 `json:"steal_p"`
+	Guest   float64 `json:"guest_p"`
+}
+
+// StatCounters holds the system-wide scheduler counters reported in
+// /proc/stat alongside the per-cpu jiffy counts.
+type StatCounters struct {
+	Ctxt         uint64 `json:"ctxt"`
+	Intr         uint64 `json:"intr"`
+	Processes    uint64 `json:"processes"`
+	ProcsRunning uint64 `json:"procs_running"`
+	ProcsBlocked uint64 `json:"procs_blocked"`
+}
+
+// MemStat holds memory (or swap) usage in bytes, plus the derived usage
+// percentage.
+type MemStat struct {
+	Total  uint64
+	Used   uint64
+	Free   uint64
+	Actual struct {
+		Used uint64
+		Free uint64
+	} `json:"actual,omitempty"`
+
+	UsedPercent float64 `json:"used_p"`
+}
+
+// LoadStat holds the 1, 5 and 15 minute system load averages.
+type LoadStat struct {
+	Load1  float64 `json:"load1"`
+	Load5  float64 `json:"load5"`
+	Load15 float64 `json:"load15"`
+}
+
+// UptimeStat holds how long the system has been running, and when it
+// booted.
+type UptimeStat struct {
+	Duration time.Duration
+	BootTime time.Time
+}
+
+// Format renders the uptime as a short human string, e.g. "3d 4h 12m".
+// Days are omitted when zero, and hours when both days and hours are zero;
+// minutes are always shown.
+func (u UptimeStat) Format() string {
+
+	d := u.Duration
+	days := int64(d / (24 * time.Hour))
+	d -= time.Duration(days) * 24 * time.Hour
+	hours := int64(d / time.Hour)
+	d -= time.Duration(hours) * time.Hour
+	minutes := int64(d / time.Minute)
+
+	var parts []string
+	if days > 0 {
+		parts = append(parts, fmt.Sprintf("%dd", days))
+	}
+	if days > 0 || hours > 0 {
+		parts = append(parts, fmt.Sprintf("%dh", hours))
+	}
+	parts = append(parts, fmt.Sprintf("%dm", minutes))
+
+	return strings.Join(parts, " ")
+}